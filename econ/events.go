@@ -32,14 +32,34 @@ type MessagePublishedData struct {
 // Returns an error if the CloudEvent payload cannot be parsed or if
 // the data cannot be unmarshaled into v.
 func EventToStruct(e event.Event, v any) error {
-	var msg MessagePublishedData
-	if err := e.DataAs(&msg); err != nil {
-		return fmt.Errorf("failed to parse pubsub message wrapper: %w", err)
+	_, err := EventToStructWithAttrs(e, v)
+	return err
+}
+
+// EventToStructWithAttrs does what EventToStruct does and additionally
+// returns the wrapped Pub/Sub message's Attributes, so callers can branch on
+// things like googclient_schemaencoding, ce-subject, or a tenant ID without
+// re-parsing the CloudEvent wrapper themselves.
+func EventToStructWithAttrs(e event.Event, v any) (map[string]string, error) {
+	msg, err := unwrapPubsubMessage(e)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := json.Unmarshal(msg.Message.Data, v); err != nil {
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+	if err := json.Unmarshal(msg.Data, v); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
 	}
 
-	return nil
+	return msg.Attributes, nil
+}
+
+// unwrapPubsubMessage extracts the Pub/Sub message wrapped inside e, without
+// decoding its Data payload. EventToStructWithAttrs and Router.Dispatch both
+// build on this so the wrapper-parsing error path stays in one place.
+func unwrapPubsubMessage(e event.Event) (PubsubMessage, error) {
+	var msg MessagePublishedData
+	if err := e.DataAs(&msg); err != nil {
+		return PubsubMessage{}, fmt.Errorf("failed to parse pubsub message wrapper: %w", err)
+	}
+	return msg.Message, nil
 }
@@ -0,0 +1,108 @@
+package econ
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+var (
+	ctxInterfaceType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errInterfaceType = reflect.TypeOf((*error)(nil)).Elem()
+	pubsubMsgType    = reflect.TypeOf(PubsubMessage{})
+)
+
+// route is the reflected form of one Register call: the type to decode a
+// dispatched event's payload into, and the handler to invoke with it.
+type route struct {
+	payloadType reflect.Type // T, not *T
+	call        reflect.Value
+}
+
+// Router dispatches CloudEvents to handlers registered per CloudEvent type.
+// Where EventToStruct decodes into a type the caller already knows, Router
+// lets a single Cloud Run/Eventarc entrypoint serve many event types, each
+// decoded into its own Go type, making econ usable as a small dispatch layer
+// rather than a one-shot decode helper.
+//
+// The zero value is not usable; construct one with NewRouter.
+type Router struct {
+	routes map[string]route
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{routes: make(map[string]route)}
+}
+
+// Register associates eventType with handler, which must have the shape
+// func(ctx context.Context, v *T, msg PubsubMessage) error for some type T
+// (typically a struct). sample is any value of T or *T; its contents are ignored, it
+// exists only so Register can recover T via reflection since Go has no
+// generic methods. Dispatch allocates a fresh *T per call, JSON-decodes the
+// event's Pub/Sub data into it, and invokes handler with it and the raw
+// PubsubMessage (so the handler can inspect Attributes directly).
+//
+// Register panics if handler doesn't have that shape or its payload type
+// doesn't match sample: a bad registration is a programming error, best
+// caught at startup rather than surfaced as a per-message dispatch error.
+func (r *Router) Register(eventType string, sample any, handler any) {
+	handlerVal := reflect.ValueOf(handler)
+	handlerType := handlerVal.Type()
+
+	if handlerType.Kind() != reflect.Func ||
+		handlerType.NumIn() != 3 || handlerType.NumOut() != 1 ||
+		!handlerType.In(0).Implements(ctxInterfaceType) ||
+		handlerType.In(1).Kind() != reflect.Ptr ||
+		handlerType.In(2) != pubsubMsgType ||
+		handlerType.Out(0) != errInterfaceType {
+		panic(fmt.Sprintf("econ: handler for %q must be func(context.Context, *T, PubsubMessage) error, got %s", eventType, handlerType))
+	}
+
+	payloadType := handlerType.In(1).Elem()
+	sampleType := reflect.TypeOf(sample)
+	if sampleType.Kind() == reflect.Ptr {
+		sampleType = sampleType.Elem()
+	}
+	if sampleType != payloadType {
+		panic(fmt.Sprintf("econ: sample type %s doesn't match handler payload type *%s", sampleType, payloadType))
+	}
+
+	r.routes[eventType] = route{payloadType: payloadType, call: handlerVal}
+}
+
+// Dispatch looks up the handler registered for e's CloudEvent type, decodes
+// e's wrapped Pub/Sub message into a fresh instance of that handler's
+// declared payload type, and invokes the handler with it and the raw
+// PubsubMessage. It returns an error without invoking anything if no handler
+// is registered for e.Type() or if decoding fails; otherwise it returns
+// whatever error the handler returns.
+func (r *Router) Dispatch(ctx context.Context, e event.Event) error {
+	rt, ok := r.routes[e.Type()]
+	if !ok {
+		return fmt.Errorf("econ: no handler registered for event type %q", e.Type())
+	}
+
+	msg, err := unwrapPubsubMessage(e)
+	if err != nil {
+		return err
+	}
+
+	payload := reflect.New(rt.payloadType)
+	if err := json.Unmarshal(msg.Data, payload.Interface()); err != nil {
+		return fmt.Errorf("decoding event type %q: %w", e.Type(), err)
+	}
+
+	out := rt.call.Call([]reflect.Value{
+		reflect.ValueOf(ctx),
+		payload,
+		reflect.ValueOf(msg),
+	})
+	if errVal := out[0].Interface(); errVal != nil {
+		return errVal.(error)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package econ
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+type routerTestWidget struct {
+	ID string `json:"id"`
+}
+
+func newRoutedEvent(t *testing.T, eventType string, data []byte, attrs map[string]string) event.Event {
+	t.Helper()
+
+	rawData, err := json.Marshal(map[string]string{"data": base64.StdEncoding.EncodeToString(data)})
+	if err != nil {
+		t.Fatalf("marshaling pubsub message: %v", err)
+	}
+	if attrs != nil {
+		var msg map[string]any
+		if err := json.Unmarshal(rawData, &msg); err != nil {
+			t.Fatalf("unmarshaling pubsub message: %v", err)
+		}
+		msg["attributes"] = attrs
+		rawData, err = json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("remarshaling pubsub message: %v", err)
+		}
+	}
+
+	wrapped, err := json.Marshal(map[string]json.RawMessage{"message": rawData})
+	if err != nil {
+		t.Fatalf("marshaling MessagePublishedData: %v", err)
+	}
+
+	e := event.New()
+	e.SetType(eventType)
+	e.SetSource("test")
+	e.SetID("1")
+	if err := e.SetData("application/json", wrapped); err != nil {
+		t.Fatalf("SetData: %v", err)
+	}
+	return e
+}
+
+func TestRouterDispatchInvokesRegisteredHandler(t *testing.T) {
+	r := NewRouter()
+
+	var got routerTestWidget
+	var gotAttrs map[string]string
+	r.Register("widget.created", routerTestWidget{}, func(ctx context.Context, v *routerTestWidget, msg PubsubMessage) error {
+		got = *v
+		gotAttrs = msg.Attributes
+		return nil
+	})
+
+	e := newRoutedEvent(t, "widget.created", []byte(`{"id":"abc"}`), map[string]string{"tenant": "acme"})
+
+	if err := r.Dispatch(context.Background(), e); err != nil {
+		t.Fatalf("Dispatch() = %v, want nil", err)
+	}
+	if got.ID != "abc" {
+		t.Fatalf("got.ID = %q, want %q", got.ID, "abc")
+	}
+	if gotAttrs["tenant"] != "acme" {
+		t.Fatalf("gotAttrs[tenant] = %q, want %q", gotAttrs["tenant"], "acme")
+	}
+}
+
+func TestRouterDispatchPropagatesHandlerError(t *testing.T) {
+	r := NewRouter()
+	wantErr := errors.New("handler failed")
+	r.Register("widget.created", routerTestWidget{}, func(ctx context.Context, v *routerTestWidget, msg PubsubMessage) error {
+		return wantErr
+	})
+
+	e := newRoutedEvent(t, "widget.created", []byte(`{"id":"abc"}`), nil)
+
+	if err := r.Dispatch(context.Background(), e); !errors.Is(err, wantErr) {
+		t.Fatalf("Dispatch() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestRouterDispatchUnregisteredTypeErrors(t *testing.T) {
+	r := NewRouter()
+	e := newRoutedEvent(t, "widget.deleted", []byte(`{}`), nil)
+
+	if err := r.Dispatch(context.Background(), e); err == nil {
+		t.Fatalf("Dispatch() = nil, want error for unregistered event type")
+	}
+}
+
+func TestRouterRegisterPanicsOnWrongHandlerShape(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Register() did not panic on a malformed handler")
+		}
+	}()
+
+	r := NewRouter()
+	r.Register("widget.created", routerTestWidget{}, func(v routerTestWidget) error { return nil })
+}
+
+func TestRouterRegisterPanicsOnSampleTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Register() did not panic on a sample/handler payload type mismatch")
+		}
+	}()
+
+	type other struct{}
+	r := NewRouter()
+	r.Register("widget.created", other{}, func(ctx context.Context, v *routerTestWidget, msg PubsubMessage) error { return nil })
+}
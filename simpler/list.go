@@ -0,0 +1,132 @@
+package simpler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/hamba/avro/v2"
+	"google.golang.org/api/iterator"
+)
+
+// ListQuery filters and shapes an object listing passed to StorageClient.List.
+type ListQuery struct {
+	// Prefix restricts listing to objects whose name starts with this value.
+	Prefix string
+
+	// Delimiter, if set, collapses names sharing a prefix up to the first
+	// occurrence of the delimiter into a single returned entry.
+	Delimiter string
+
+	// StartOffset restricts listing to object names >= this value, lexically.
+	StartOffset string
+
+	// EndOffset restricts listing to object names < this value, lexically.
+	EndOffset string
+
+	// MatchGlob filters object names using a glob pattern.
+	MatchGlob string
+}
+
+// ObjectInfo is a typed summary of a GCS object returned by List.
+type ObjectInfo struct {
+	Name        string
+	Size        int64
+	ContentType string
+	Metadata    map[string]string
+	Updated     time.Time
+	Generation  int64
+}
+
+func objectInfoFromAttrs(attrs *storage.ObjectAttrs) ObjectInfo {
+	return ObjectInfo{
+		Name:        attrs.Name,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+		Metadata:    attrs.Metadata,
+		Updated:     attrs.Updated,
+		Generation:  attrs.Generation,
+	}
+}
+
+// ObjectIterator yields ObjectInfo values one at a time. Next returns
+// iterator.Done (google.golang.org/api/iterator) once listing is complete.
+type ObjectIterator interface {
+	Next() (ObjectInfo, error)
+}
+
+type objectIterator struct {
+	it *storage.ObjectIterator
+}
+
+func (i *objectIterator) Next() (ObjectInfo, error) {
+	attrs, err := i.it.Next()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return objectInfoFromAttrs(attrs), nil
+}
+
+// List returns an iterator over the objects in bucket matching query.
+func (c *storageClient) List(ctx context.Context, bucket string, query ListQuery) ObjectIterator {
+	it := c.client.Bucket(bucket).Objects(ctx, &storage.Query{
+		Prefix:      query.Prefix,
+		Delimiter:   query.Delimiter,
+		StartOffset: query.StartOffset,
+		EndOffset:   query.EndOffset,
+		MatchGlob:   query.MatchGlob,
+	})
+	return &objectIterator{it: it}
+}
+
+// ListSchematized lists the objects under prefix in bucket whose schema_ref
+// metadata (written by UploadJSONSchematized) matches T's schema name,
+// decoding and validating each one. It returns a closure that yields
+// successive values; the closure's bool return is false once there are no
+// objects left. T must be a pointer type implementing SchemaProvider, e.g.
+// ListSchematized[*MyEvent](ctx, client, bucket, prefix).
+func ListSchematized[T SchemaProvider](ctx context.Context, c StorageClient, bucket, prefix string) (func() (T, bool, error), error) {
+	zeroType := reflect.TypeOf((*T)(nil)).Elem()
+	if zeroType.Kind() != reflect.Ptr {
+		var zero T
+		return nil, fmt.Errorf("ListSchematized requires a pointer type, got %T", zero)
+	}
+
+	sample := reflect.New(zeroType.Elem()).Interface().(T)
+	ns, ok := sample.Schema().(avro.NamedSchema)
+	if !ok {
+		return nil, fmt.Errorf("schema for %T is not a named schema", sample)
+	}
+	wantSchema := ns.Name()
+
+	it := c.List(ctx, bucket, ListQuery{Prefix: prefix})
+
+	next := func() (T, bool, error) {
+		for {
+			info, err := it.Next()
+			if errors.Is(err, iterator.Done) {
+				var none T
+				return none, false, nil
+			}
+			if err != nil {
+				var none T
+				return none, false, err
+			}
+			if info.Metadata[schemaRefKey] != wantSchema {
+				continue
+			}
+
+			v := reflect.New(zeroType.Elem()).Interface().(T)
+			if err := c.DownloadJSONSchematized(ctx, bucket, info.Name, v); err != nil {
+				var none T
+				return none, false, err
+			}
+			return v, true, nil
+		}
+	}
+
+	return next, nil
+}
@@ -0,0 +1,122 @@
+package simpler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"cloud.google.com/go/storage"
+	"github.com/hamba/avro/v2"
+	"google.golang.org/api/iterator"
+)
+
+type listTestWidget struct {
+	ID string `json:"id"`
+}
+
+func (w *listTestWidget) Schema() avro.Schema {
+	return avro.MustParse(`{"type":"record","name":"Widget","namespace":"test","fields":[{"name":"id","type":"string"}]}`)
+}
+
+// fakeObjectIterator yields a fixed slice of ObjectInfo, then iterator.Done.
+type fakeObjectIterator struct {
+	infos []ObjectInfo
+	pos   int
+}
+
+func (f *fakeObjectIterator) Next() (ObjectInfo, error) {
+	if f.pos >= len(f.infos) {
+		return ObjectInfo{}, iterator.Done
+	}
+	info := f.infos[f.pos]
+	f.pos++
+	return info, nil
+}
+
+// fakeListStorageClient implements StorageClient, backed by an in-memory
+// object list and bodies keyed by name, just enough to exercise
+// ListSchematized: List and DownloadJSONSchematized.
+type fakeListStorageClient struct {
+	objects []ObjectInfo
+	bodies  map[string][]byte
+}
+
+func (f *fakeListStorageClient) List(ctx context.Context, bucket string, query ListQuery) ObjectIterator {
+	return &fakeObjectIterator{infos: f.objects}
+}
+
+func (f *fakeListStorageClient) DownloadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider, opts ...DownloadOption) error {
+	return json.Unmarshal(f.bodies[name], object)
+}
+
+func (f *fakeListStorageClient) UploadFile(ctx context.Context, bucket, name string, content io.Reader, opts ...UploadOption) error {
+	return nil
+}
+
+func (f *fakeListStorageClient) UploadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider, opts ...UploadOption) error {
+	return nil
+}
+
+func (f *fakeListStorageClient) DownloadFile(ctx context.Context, bucket, name string, opts ...DownloadOption) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *fakeListStorageClient) UploadStream(ctx context.Context, bucket, name string, r io.Reader, opts ...UploadOption) (*storage.ObjectAttrs, error) {
+	return nil, nil
+}
+
+func (f *fakeListStorageClient) DownloadStream(ctx context.Context, bucket, name string) (io.ReadCloser, *storage.ObjectAttrs, error) {
+	return nil, nil, nil
+}
+
+func (f *fakeListStorageClient) DownloadRange(ctx context.Context, bucket, name string, offset, length int64) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+// listTestWidgetValue has a value-receiver Schema method, so it can be used
+// to exercise ListSchematized's rejection of non-pointer type parameters
+// (listTestWidget's Schema method has a pointer receiver, so only *listTestWidget
+// satisfies SchemaProvider and could be passed as a type argument at all).
+type listTestWidgetValue struct{}
+
+func (listTestWidgetValue) Schema() avro.Schema {
+	return avro.MustParse(`{"type":"record","name":"Widget","namespace":"test","fields":[]}`)
+}
+
+func TestListSchematizedRequiresPointerType(t *testing.T) {
+	_, err := ListSchematized[listTestWidgetValue](context.Background(), &fakeListStorageClient{}, "bucket", "prefix/")
+	if err == nil {
+		t.Fatalf("ListSchematized() = nil error, want error for non-pointer type")
+	}
+}
+
+func TestListSchematizedFiltersBySchemaRefAndDecodes(t *testing.T) {
+	store := &fakeListStorageClient{
+		objects: []ObjectInfo{
+			{Name: "a", Metadata: map[string]string{schemaRefKey: "OtherSchema"}},
+			{Name: "b", Metadata: map[string]string{schemaRefKey: "Widget"}},
+		},
+		bodies: map[string][]byte{
+			"b": []byte(`{"id":"abc"}`),
+		},
+	}
+
+	next, err := ListSchematized[*listTestWidget](context.Background(), store, "bucket", "prefix/")
+	if err != nil {
+		t.Fatalf("ListSchematized() error: %v", err)
+	}
+
+	got, ok, err := next()
+	if err != nil || !ok {
+		t.Fatalf("next() = %v, %v, %v, want the one matching object", got, ok, err)
+	}
+	if got.ID != "abc" {
+		t.Fatalf("got.ID = %q, want %q", got.ID, "abc")
+	}
+
+	_, ok, err = next()
+	if err != nil || ok {
+		t.Fatalf("next() = _, %v, %v, want ok=false once objects are exhausted", ok, err)
+	}
+}
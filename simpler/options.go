@@ -0,0 +1,109 @@
+package simpler
+
+import (
+	"net/http"
+	"os"
+
+	"google.golang.org/api/option"
+)
+
+// clientConfig holds accumulated settings for constructing a StorageClient
+// or PubSubClient.
+type clientConfig struct {
+	clientOptions  []option.ClientOption
+	retryPolicy    RetryPolicy
+	schemaRegistry SchemaRegistry
+}
+
+// Option configures a StorageClient or PubSubClient at construction time.
+type Option func(*clientConfig)
+
+// WithHTTPClient makes the client use hc for all requests instead of the
+// default authenticated transport.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *clientConfig) {
+		c.clientOptions = append(c.clientOptions, option.WithHTTPClient(hc))
+	}
+}
+
+// WithoutAuthentication disables authentication, for use against local
+// emulators and fakes that don't check credentials.
+func WithoutAuthentication() Option {
+	return func(c *clientConfig) {
+		c.clientOptions = append(c.clientOptions, option.WithoutAuthentication())
+	}
+}
+
+// WithCredentialsFile makes the client use the service account or other
+// credentials file at path instead of the ambient application default
+// credentials.
+func WithCredentialsFile(path string) Option {
+	return func(c *clientConfig) {
+		c.clientOptions = append(c.clientOptions, option.WithCredentialsFile(path))
+	}
+}
+
+// WithEndpoint overrides the API endpoint the client talks to, e.g. to point
+// at fake-gcs-server or the Pub/Sub emulator directly.
+func WithEndpoint(url string) Option {
+	return func(c *clientConfig) {
+		c.clientOptions = append(c.clientOptions, option.WithEndpoint(url))
+	}
+}
+
+// storageEmulatorOptions returns the client options needed to talk to
+// fake-gcs-server when STORAGE_EMULATOR_HOST is set, or nil otherwise.
+func storageEmulatorOptions() []option.ClientOption {
+	host := os.Getenv("STORAGE_EMULATOR_HOST")
+	if host == "" {
+		return nil
+	}
+
+	return []option.ClientOption{
+		option.WithEndpoint("http://" + host + "/storage/v1/"),
+		option.WithoutAuthentication(),
+	}
+}
+
+// pubsubEmulatorOptions returns the client options needed to talk to the
+// Pub/Sub emulator when PUBSUB_EMULATOR_HOST is set, or nil otherwise.
+func pubsubEmulatorOptions() []option.ClientOption {
+	host := os.Getenv("PUBSUB_EMULATOR_HOST")
+	if host == "" {
+		return nil
+	}
+
+	return []option.ClientOption{
+		option.WithEndpoint(host),
+		option.WithoutAuthentication(),
+	}
+}
+
+// WithRetry sets the default retry policy applied around upload/download
+// paths on a StorageClient. It can be overridden per call with
+// UploadOption/DownloadOption.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *clientConfig) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithSchemaRegistry attaches a SchemaRegistry to a StorageClient, so
+// UploadJSONSchematized/DownloadJSONSchematized record and resolve schemas
+// by fingerprint instead of only checking the schema name.
+func WithSchemaRegistry(registry SchemaRegistry) Option {
+	return func(c *clientConfig) {
+		c.schemaRegistry = registry
+	}
+}
+
+// newClientConfig builds a clientConfig from emulatorOpts (detected from the
+// environment) followed by the caller-supplied opts, so explicit options win
+// over emulator auto-detection.
+func newClientConfig(emulatorOpts []option.ClientOption, opts []Option) *clientConfig {
+	cfg := &clientConfig{clientOptions: emulatorOpts, retryPolicy: defaultRetryPolicy()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
@@ -0,0 +1,65 @@
+package simpler
+
+import "testing"
+
+func TestStorageEmulatorOptionsUnset(t *testing.T) {
+	t.Setenv("STORAGE_EMULATOR_HOST", "")
+	if opts := storageEmulatorOptions(); opts != nil {
+		t.Fatalf("storageEmulatorOptions() = %v, want nil", opts)
+	}
+}
+
+func TestStorageEmulatorOptionsSet(t *testing.T) {
+	t.Setenv("STORAGE_EMULATOR_HOST", "localhost:9000")
+	if opts := storageEmulatorOptions(); len(opts) != 2 {
+		t.Fatalf("storageEmulatorOptions() = %v, want 2 options", opts)
+	}
+}
+
+func TestPubsubEmulatorOptionsUnset(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "")
+	if opts := pubsubEmulatorOptions(); opts != nil {
+		t.Fatalf("pubsubEmulatorOptions() = %v, want nil", opts)
+	}
+}
+
+func TestPubsubEmulatorOptionsSet(t *testing.T) {
+	t.Setenv("PUBSUB_EMULATOR_HOST", "localhost:8085")
+	if opts := pubsubEmulatorOptions(); len(opts) != 2 {
+		t.Fatalf("pubsubEmulatorOptions() = %v, want 2 options", opts)
+	}
+}
+
+func TestNewClientConfigAppliesCallerOptions(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 7}
+	registry := NewMemorySchemaRegistry()
+
+	cfg := newClientConfig(nil, []Option{
+		WithRetry(policy),
+		WithSchemaRegistry(registry),
+	})
+
+	if cfg.retryPolicy.MaxAttempts != policy.MaxAttempts {
+		t.Fatalf("retryPolicy.MaxAttempts = %d, want %d", cfg.retryPolicy.MaxAttempts, policy.MaxAttempts)
+	}
+	if cfg.schemaRegistry != registry {
+		t.Fatalf("schemaRegistry not set to the provided registry")
+	}
+}
+
+func TestNewClientConfigDefaultRetryPolicy(t *testing.T) {
+	cfg := newClientConfig(nil, nil)
+	if cfg.retryPolicy.MaxAttempts != defaultRetryPolicy().MaxAttempts {
+		t.Fatalf("retryPolicy.MaxAttempts = %d, want default %d", cfg.retryPolicy.MaxAttempts, defaultRetryPolicy().MaxAttempts)
+	}
+}
+
+func TestNewClientConfigPreservesEmulatorOptions(t *testing.T) {
+	t.Setenv("STORAGE_EMULATOR_HOST", "localhost:9000")
+	emulatorOpts := storageEmulatorOptions()
+
+	cfg := newClientConfig(emulatorOpts, nil)
+	if len(cfg.clientOptions) != len(emulatorOpts) {
+		t.Fatalf("clientOptions = %d entries, want %d", len(cfg.clientOptions), len(emulatorOpts))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 )
@@ -14,9 +15,15 @@ type PubSubClient struct {
 	client *pubsub.Client
 }
 
-// NewPubSubClient creates a new PubSubGateway instance.
-func NewPubSubClient(ctx context.Context, projectID string) (*PubSubClient, error) {
-	client, err := pubsub.NewClient(ctx, projectID)
+// NewPubSubClient creates a new PubSubGateway instance. By default it
+// authenticates using application default credentials; pass Options to
+// customize the transport or point it at an emulator. If PUBSUB_EMULATOR_HOST
+// is set, it is honored automatically unless overridden by an explicit
+// WithEndpoint option.
+func NewPubSubClient(ctx context.Context, projectID string, opts ...Option) (*PubSubClient, error) {
+	cfg := newClientConfig(pubsubEmulatorOptions(), opts)
+
+	client, err := pubsub.NewClient(ctx, projectID, cfg.clientOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("creating pubsub client: %w", err)
 	}
@@ -49,3 +56,97 @@ func (c *PubSubClient) PublishMessage(ctx context.Context, topicID string, objec
 
 	return nil
 }
+
+// ReceiveSettings configures how a subscription pulls and processes messages.
+// It mirrors the subset of pubsub.ReceiveSettings callers most commonly need
+// to tune; zero values leave the pubsub package default in place.
+type ReceiveSettings struct {
+	// MaxOutstandingMessages caps the number of unacknowledged messages the
+	// client will hold in flight at once.
+	MaxOutstandingMessages int
+
+	// NumGoroutines controls how many goroutines pull and process messages
+	// concurrently.
+	NumGoroutines int
+
+	// MaxExtension is the longest period an unacknowledged message's ack
+	// deadline will be extended for.
+	MaxExtension time.Duration
+}
+
+// apply copies the configured fields onto sub's receive settings, leaving
+// unset (zero) fields at the pubsub package default.
+func (s ReceiveSettings) apply(sub *pubsub.Subscription) {
+	if s.MaxOutstandingMessages != 0 {
+		sub.ReceiveSettings.MaxOutstandingMessages = s.MaxOutstandingMessages
+	}
+	if s.NumGoroutines != 0 {
+		sub.ReceiveSettings.NumGoroutines = s.NumGoroutines
+	}
+	if s.MaxExtension != 0 {
+		sub.ReceiveSettings.MaxExtension = s.MaxExtension
+	}
+}
+
+// subscribeConfig holds accumulated settings for a single Subscribe call.
+type subscribeConfig struct {
+	receiveSettings ReceiveSettings
+}
+
+// SubscribeOption configures a Subscribe or SubscribeAs call.
+type SubscribeOption func(*subscribeConfig)
+
+// WithReceiveSettings overrides the subscription's receive settings for this
+// Subscribe call.
+func WithReceiveSettings(s ReceiveSettings) SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.receiveSettings = s
+	}
+}
+
+// Handler processes a single Pub/Sub message delivered by Subscribe.
+// Returning nil acks the message; returning an error nacks it so Pub/Sub
+// will redeliver it.
+type Handler func(ctx context.Context, data []byte, attrs map[string]string, orderingKey string) error
+
+// Subscribe pulls messages from the given subscription and invokes handler
+// for each one, acking on nil error and nacking otherwise. It blocks until
+// ctx is cancelled or an unrecoverable error occurs, matching the semantics
+// of the underlying pubsub.Subscription.Receive.
+func (c *PubSubClient) Subscribe(ctx context.Context, subscriptionID string, handler Handler, opts ...SubscribeOption) error {
+	cfg := subscribeConfig{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sub := c.client.Subscription(subscriptionID)
+	cfg.receiveSettings.apply(sub)
+
+	err := sub.Receive(ctx, func(ctx context.Context, msg *pubsub.Message) {
+		if err := handler(ctx, msg.Data, msg.Attributes, msg.OrderingKey); err != nil {
+			msg.Nack()
+			return
+		}
+		msg.Ack()
+	})
+	if err != nil {
+		return fmt.Errorf("receiving from subscription %s: %w", subscriptionID, err)
+	}
+
+	return nil
+}
+
+// SubscribeAs is a typed convenience wrapper around PubSubClient.Subscribe
+// that JSON-unmarshals each message's data into a fresh T before invoking
+// handler. Go does not allow generic methods, so this is a package-level
+// function taking the client as its second argument.
+func SubscribeAs[T any](ctx context.Context, c *PubSubClient, subscriptionID string, handler func(ctx context.Context, payload T, attrs map[string]string, orderingKey string) error, opts ...SubscribeOption) error {
+	return c.Subscribe(ctx, subscriptionID, func(ctx context.Context, data []byte, attrs map[string]string, orderingKey string) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return fmt.Errorf("unmarshaling message payload: %w", err)
+		}
+
+		return handler(ctx, payload, attrs, orderingKey)
+	}, opts...)
+}
@@ -0,0 +1,116 @@
+package simpler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newTestPubSubClient starts an in-process pstest fake and returns a
+// PubSubClient wired up to it, after creating topicID and a subscription
+// subID on it. The caller is responsible for calling the returned cleanup.
+func newTestPubSubClient(t *testing.T, topicID, subID string) (*PubSubClient, func()) {
+	t.Helper()
+
+	srv := pstest.NewServer()
+	conn, err := grpc.NewClient(srv.Addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dialing pstest server: %v", err)
+	}
+
+	ctx := context.Background()
+	raw, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("creating pubsub client: %v", err)
+	}
+
+	topic, err := raw.CreateTopic(ctx, topicID)
+	if err != nil {
+		t.Fatalf("creating topic: %v", err)
+	}
+	if _, err := raw.CreateSubscription(ctx, subID, pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+		t.Fatalf("creating subscription: %v", err)
+	}
+
+	c := &PubSubClient{client: raw}
+	cleanup := func() {
+		raw.Close()
+		conn.Close()
+		srv.Close()
+	}
+	return c, cleanup
+}
+
+func TestPublishMessageAndSubscribe(t *testing.T) {
+	c, cleanup := newTestPubSubClient(t, "topic", "sub")
+	defer cleanup()
+
+	type payload struct {
+		Value string `json:"value"`
+	}
+
+	if err := c.PublishMessage(context.Background(), "topic", payload{Value: "hi"}); err != nil {
+		t.Fatalf("PublishMessage() = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan payload, 1)
+	err := SubscribeAs(ctx, c, "sub", func(ctx context.Context, got payload, attrs map[string]string, orderingKey string) error {
+		received <- got
+		cancel()
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		t.Fatalf("SubscribeAs() = %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got.Value != "hi" {
+			t.Fatalf("got.Value = %q, want %q", got.Value, "hi")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("handler was never invoked")
+	}
+}
+
+func TestReceiveSettingsApply(t *testing.T) {
+	c, cleanup := newTestPubSubClient(t, "topic2", "sub2")
+	defer cleanup()
+
+	sub := c.client.Subscription("sub2")
+	settings := ReceiveSettings{MaxOutstandingMessages: 42, NumGoroutines: 3, MaxExtension: 30 * time.Second}
+	settings.apply(sub)
+
+	if sub.ReceiveSettings.MaxOutstandingMessages != 42 {
+		t.Errorf("MaxOutstandingMessages = %d, want 42", sub.ReceiveSettings.MaxOutstandingMessages)
+	}
+	if sub.ReceiveSettings.NumGoroutines != 3 {
+		t.Errorf("NumGoroutines = %d, want 3", sub.ReceiveSettings.NumGoroutines)
+	}
+	if sub.ReceiveSettings.MaxExtension != 30*time.Second {
+		t.Errorf("MaxExtension = %v, want %v", sub.ReceiveSettings.MaxExtension, 30*time.Second)
+	}
+}
+
+func TestReceiveSettingsApplyLeavesZeroFieldsAtDefault(t *testing.T) {
+	c, cleanup := newTestPubSubClient(t, "topic3", "sub3")
+	defer cleanup()
+
+	sub := c.client.Subscription("sub3")
+	want := sub.ReceiveSettings
+
+	ReceiveSettings{}.apply(sub)
+
+	if sub.ReceiveSettings != want {
+		t.Errorf("apply() of a zero-value ReceiveSettings changed sub.ReceiveSettings: got %+v, want %+v", sub.ReceiveSettings, want)
+	}
+}
@@ -0,0 +1,115 @@
+package simpler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures how transient failures are retried around storage
+// upload/download paths.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// A value <= 1 disables retrying.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. Subsequent retries
+	// double this delay, capped at MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries.
+	MaxBackoff time.Duration
+
+	// Jitter adds up to this much additional random delay to each retry,
+	// to avoid retry storms across concurrent callers.
+	Jitter time.Duration
+
+	// Retryable classifies whether err is worth retrying. If nil,
+	// defaultRetryable is used.
+	Retryable func(err error) bool
+}
+
+// defaultRetryPolicy is used when callers don't configure one explicitly.
+func defaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         100 * time.Millisecond,
+	}
+}
+
+// isRetryable reports whether err should be retried under this policy.
+func (p RetryPolicy) isRetryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return defaultRetryable(err)
+}
+
+// defaultRetryable retries 429/5xx GCS responses, the equivalent gRPC status
+// codes, and io.ErrUnexpectedEOF (a truncated read/write worth retrying).
+func defaultRetryable(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code == http.StatusTooManyRequests || gerr.Code >= http.StatusInternalServerError
+	}
+
+	if st, ok := status.FromError(err); ok {
+		switch st.Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Internal, codes.Aborted, codes.DeadlineExceeded:
+			return true
+		}
+	}
+
+	return false
+}
+
+// withRetry runs fn, retrying according to policy until it succeeds, ctx is
+// cancelled, or the policy's attempts are exhausted.
+func withRetry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+
+	backoff := policy.InitialBackoff
+	var err error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+
+		if attempt == policy.MaxAttempts-1 || !policy.isRetryable(err) {
+			return err
+		}
+
+		wait := backoff
+		if policy.Jitter > 0 {
+			wait += time.Duration(rand.Int63n(int64(policy.Jitter)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+
+	return err
+}
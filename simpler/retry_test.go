@@ -0,0 +1,122 @@
+package simpler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithRetrySucceedsWithoutRetrying(t *testing.T) {
+	calls := 0
+	err := withRetry(context.Background(), RetryPolicy{MaxAttempts: 3}, func() error {
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry() = %v, want nil", err)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
+
+func TestWithRetryRetriesRetryableError(t *testing.T) {
+	calls := 0
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Retryable:      func(error) bool { return true },
+	}
+
+	err := withRetry(context.Background(), policy, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("transient")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("withRetry() = %v, want nil", err)
+	}
+	if calls != 3 {
+		t.Fatalf("calls = %d, want 3", calls)
+	}
+}
+
+func TestWithRetryStopsOnNonRetryableError(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("fatal")
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(error) bool { return false },
+	}
+
+	err := withRetry(context.Background(), policy, func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1 (no retry on non-retryable error)", calls)
+	}
+}
+
+func TestWithRetryExhaustsAttempts(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("always fails")
+	policy := RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Retryable:      func(error) bool { return true },
+	}
+
+	err := withRetry(context.Background(), policy, func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != policy.MaxAttempts {
+		t.Fatalf("calls = %d, want %d", calls, policy.MaxAttempts)
+	}
+}
+
+func TestWithRetryRespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 50 * time.Millisecond,
+		MaxBackoff:     50 * time.Millisecond,
+		Retryable:      func(error) bool { return true },
+	}
+
+	err := withRetry(ctx, policy, func() error {
+		cancel()
+		return errors.New("transient")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithRetryZeroMaxAttemptsRunsOnce(t *testing.T) {
+	calls := 0
+	wantErr := errors.New("fails")
+
+	err := withRetry(context.Background(), RetryPolicy{}, func() error {
+		calls++
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("calls = %d, want 1", calls)
+	}
+}
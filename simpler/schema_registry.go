@@ -0,0 +1,310 @@
+package simpler
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/storage"
+	"github.com/hamba/avro/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// schemaFPKey is the object metadata key storing the hex-encoded Rabin
+// fingerprint of the schema a JSON-schematized object was written with.
+// schemaIDKey optionally carries a registry-assigned ID alongside it.
+const (
+	schemaFPKey = "schema_fp"
+	schemaIDKey = "schema_id"
+)
+
+// SchemaRegistration describes a schema as tracked by a SchemaRegistry.
+type SchemaRegistration struct {
+	// Fingerprint is the hex-encoded 64-bit Rabin fingerprint of the
+	// schema's canonical form, as defined by the Avro spec's "Schema
+	// Fingerprints" appendix. It is stable across processes: re-registering
+	// an identical schema always yields the same Fingerprint.
+	Fingerprint string
+
+	// ID is an optional registry-assigned identifier for the schema. Not
+	// every SchemaRegistry implementation assigns one; callers that don't
+	// need it can ignore an empty ID.
+	ID string
+}
+
+// SchemaRegistry assigns stable fingerprints to Avro schemas so that object
+// metadata can record exactly which version of a schema an object was
+// written with, and so a reader can later look up that writer schema to
+// decode older-or-newer-but-compatible records.
+type SchemaRegistry interface {
+	// Register records schema and returns its registration. Registering an
+	// already-known schema is a no-op that returns the existing
+	// registration. If schema's name was previously registered with a
+	// different schema, Register runs CheckCompatibility against the most
+	// recent registration for that name and rejects an incompatible change.
+	Register(ctx context.Context, schema avro.Schema) (SchemaRegistration, error)
+
+	// Get returns the schema previously registered under fingerprint. It
+	// returns an error if no schema is known for that fingerprint.
+	Get(ctx context.Context, fingerprint string) (avro.Schema, error)
+}
+
+// fingerprintSchema returns the hex-encoded 64-bit Rabin ("CRC-64-AVRO")
+// fingerprint of schema's canonical form, per the Avro spec's "Schema
+// Fingerprints" appendix. Two schemas with the same fingerprint are
+// considered the same schema.
+func fingerprintSchema(schema avro.Schema) (string, error) {
+	fp, err := schema.FingerprintUsing(avro.CRC64Avro)
+	if err != nil {
+		return "", fmt.Errorf("fingerprinting schema: %w", err)
+	}
+	return hex.EncodeToString(fp), nil
+}
+
+// CheckCompatibility reports whether evolving a schema from prev to next is
+// BACKWARD-compatible: a reader on next must still be able to decode data
+// already written under prev. That's the direction Register needs: data
+// already stored under prev isn't rewritten when a newer schema is
+// registered, so next has to keep being able to read it. It delegates to
+// hamba/avro's own schema resolution rules via avro.SchemaCompatibility, so
+// standard Avro promotions (e.g. int -> long, string <-> bytes) are accepted
+// rather than rejected as type changes.
+func CheckCompatibility(prev, next avro.Schema) error {
+	if err := avro.NewSchemaCompatibility().Compatible(next, prev); err != nil {
+		return fmt.Errorf("incompatible schema evolution: next can't read data written under prev, breaks BACKWARD compatibility: %w", err)
+	}
+	return nil
+}
+
+// resolveAndUnmarshal decodes data (JSON written according to writerSchema)
+// into object (shaped by readerSchema). When the two schemas differ, it
+// fills in the reader's default value for any field the writer's payload is
+// missing, so a reader on a newer-but-compatible schema can still read
+// records written by an older schema (and vice versa, since JSON simply
+// ignores fields object doesn't declare).
+func resolveAndUnmarshal(writerSchema, readerSchema avro.Schema, data []byte, object any) error {
+	readerRec, readerIsRecord := readerSchema.(*avro.RecordSchema)
+	if readerIsRecord {
+		writerFP, err := fingerprintSchema(writerSchema)
+		if err != nil {
+			return fmt.Errorf("fingerprinting writer schema: %w", err)
+		}
+		readerFP, err := fingerprintSchema(readerSchema)
+		if err != nil {
+			return fmt.Errorf("fingerprinting reader schema: %w", err)
+		}
+		if writerFP == readerFP {
+			readerIsRecord = false
+		}
+	}
+	if !readerIsRecord {
+		return json.Unmarshal(data, object)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("unmarshaling raw record: %w", err)
+	}
+
+	for _, f := range readerRec.Fields() {
+		if _, present := raw[f.Name()]; present {
+			continue
+		}
+		if !f.HasDefault() {
+			return fmt.Errorf("field %q missing from writer payload and has no reader default", f.Name())
+		}
+		def, err := json.Marshal(f.Default())
+		if err != nil {
+			return fmt.Errorf("marshaling default for field %q: %w", f.Name(), err)
+		}
+		raw[f.Name()] = def
+	}
+
+	filled, err := json.Marshal(raw)
+	if err != nil {
+		return fmt.Errorf("remarshaling resolved record: %w", err)
+	}
+
+	return json.Unmarshal(filled, object)
+}
+
+// memorySchemaRegistry is a process-local SchemaRegistry, suitable for tests
+// and single-process services that don't need to share registrations.
+type memorySchemaRegistry struct {
+	mu      sync.Mutex
+	schemas map[string]avro.Schema // fingerprint -> schema
+	ids     map[string]string      // fingerprint -> assigned ID
+	latest  map[string]string      // schema name -> fingerprint of latest registration
+	nextID  int
+}
+
+// NewMemorySchemaRegistry returns an in-memory SchemaRegistry.
+func NewMemorySchemaRegistry() SchemaRegistry {
+	return &memorySchemaRegistry{
+		schemas: make(map[string]avro.Schema),
+		ids:     make(map[string]string),
+		latest:  make(map[string]string),
+	}
+}
+
+func (r *memorySchemaRegistry) Register(ctx context.Context, schema avro.Schema) (SchemaRegistration, error) {
+	ns, ok := schema.(avro.NamedSchema)
+	if !ok {
+		return SchemaRegistration{}, fmt.Errorf("schema is not a named schema: %s", schema.Type())
+	}
+
+	fp, err := fingerprintSchema(schema)
+	if err != nil {
+		return SchemaRegistration{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id, ok := r.ids[fp]; ok {
+		return SchemaRegistration{Fingerprint: fp, ID: id}, nil
+	}
+
+	if prevFP, ok := r.latest[ns.Name()]; ok {
+		if err := CheckCompatibility(r.schemas[prevFP], schema); err != nil {
+			return SchemaRegistration{}, fmt.Errorf("registering schema %s: %w", ns.Name(), err)
+		}
+	}
+
+	r.nextID++
+	id := strconv.Itoa(r.nextID)
+	r.schemas[fp] = schema
+	r.ids[fp] = id
+	r.latest[ns.Name()] = fp
+
+	return SchemaRegistration{Fingerprint: fp, ID: id}, nil
+}
+
+func (r *memorySchemaRegistry) Get(ctx context.Context, fingerprint string) (avro.Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	schema, ok := r.schemas[fingerprint]
+	if !ok {
+		return nil, fmt.Errorf("schema %s not registered", fingerprint)
+	}
+	return schema, nil
+}
+
+// gcsSchemaRegistry is a SchemaRegistry backed by GCS, so that registrations
+// made by one service are visible to every other service sharing the bucket.
+// Each schema is stored as a single object named by its fingerprint; the
+// latest registration for each schema name is tracked via a small pointer
+// object so Register can enforce CheckCompatibility across processes.
+type gcsSchemaRegistry struct {
+	client StorageClient
+	bucket string
+	prefix string
+
+	mu    sync.Mutex
+	cache map[string]avro.Schema
+}
+
+// NewGCSSchemaRegistry returns a SchemaRegistry that stores schemas as
+// objects under prefix in bucket, via client.
+func NewGCSSchemaRegistry(client StorageClient, bucket, prefix string) SchemaRegistry {
+	return &gcsSchemaRegistry{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+		cache:  make(map[string]avro.Schema),
+	}
+}
+
+func (r *gcsSchemaRegistry) schemaObject(fingerprint string) string {
+	return r.prefix + fingerprint + ".avsc"
+}
+
+func (r *gcsSchemaRegistry) latestObject(name string) string {
+	return r.prefix + "latest/" + name
+}
+
+func (r *gcsSchemaRegistry) Register(ctx context.Context, schema avro.Schema) (SchemaRegistration, error) {
+	ns, ok := schema.(avro.NamedSchema)
+	if !ok {
+		return SchemaRegistration{}, fmt.Errorf("schema is not a named schema: %s", schema.Type())
+	}
+
+	fp, err := fingerprintSchema(schema)
+	if err != nil {
+		return SchemaRegistration{}, err
+	}
+
+	// An already-registered schema is a no-op: no compatibility check, and
+	// the latest pointer is left where it is.
+	if _, err := r.Get(ctx, fp); err == nil {
+		return SchemaRegistration{Fingerprint: fp}, nil
+	}
+
+	prevFP, err := r.client.DownloadFile(ctx, r.bucket, r.latestObject(ns.Name()))
+	switch {
+	case err == nil && string(prevFP) != fp:
+		prevSchema, err := r.Get(ctx, string(prevFP))
+		if err != nil {
+			return SchemaRegistration{}, fmt.Errorf("fetching latest schema for %s: %w", ns.Name(), err)
+		}
+		if err := CheckCompatibility(prevSchema, schema); err != nil {
+			return SchemaRegistration{}, fmt.Errorf("registering schema %s: %w", ns.Name(), err)
+		}
+	case err == nil:
+		// prevFP == fp: re-registering the same schema, nothing to check.
+	case errors.Is(err, storage.ErrObjectNotExist):
+		// first registration for this name: nothing to compare against.
+	default:
+		return SchemaRegistration{}, fmt.Errorf("fetching latest pointer for %s: %w", ns.Name(), err)
+	}
+
+	err = r.client.UploadFile(ctx, r.bucket, r.schemaObject(fp), strings.NewReader(schema.String()), WithIfDoesNotExist())
+	if err != nil {
+		var gerr *googleapi.Error
+		if !(errors.As(err, &gerr) && gerr.Code == http.StatusPreconditionFailed) {
+			return SchemaRegistration{}, fmt.Errorf("uploading schema %s: %w", fp, err)
+		}
+	}
+
+	if err := r.client.UploadFile(ctx, r.bucket, r.latestObject(ns.Name()), strings.NewReader(fp)); err != nil {
+		return SchemaRegistration{}, fmt.Errorf("updating latest pointer for %s: %w", ns.Name(), err)
+	}
+
+	r.mu.Lock()
+	r.cache[fp] = schema
+	r.mu.Unlock()
+
+	return SchemaRegistration{Fingerprint: fp}, nil
+}
+
+func (r *gcsSchemaRegistry) Get(ctx context.Context, fingerprint string) (avro.Schema, error) {
+	r.mu.Lock()
+	schema, ok := r.cache[fingerprint]
+	r.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	data, err := r.client.DownloadFile(ctx, r.bucket, r.schemaObject(fingerprint))
+	if err != nil {
+		return nil, fmt.Errorf("downloading schema %s: %w", fingerprint, err)
+	}
+
+	schema, err = avro.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema %s: %w", fingerprint, err)
+	}
+
+	r.mu.Lock()
+	r.cache[fingerprint] = schema
+	r.mu.Unlock()
+
+	return schema, nil
+}
@@ -0,0 +1,207 @@
+package simpler
+
+import (
+	"testing"
+
+	"github.com/hamba/avro/v2"
+)
+
+func mustParseSchema(t *testing.T, s string) avro.Schema {
+	t.Helper()
+	schema, err := avro.Parse(s)
+	if err != nil {
+		t.Fatalf("parsing schema: %v", err)
+	}
+	return schema
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	widgetV1 := `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"},
+		{"name":"count","type":"int"}
+	]}`
+
+	tests := []struct {
+		name    string
+		prev    string
+		next    string
+		wantErr bool
+	}{
+		{
+			name: "identical schema",
+			prev: widgetV1,
+			next: widgetV1,
+		},
+		{
+			name: "add field with default",
+			prev: widgetV1,
+			next: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"},
+				{"name":"count","type":"int"},
+				{"name":"note","type":"string","default":""}
+			]}`,
+		},
+		{
+			name: "add field without default",
+			prev: widgetV1,
+			next: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"},
+				{"name":"count","type":"int"},
+				{"name":"note","type":"string"}
+			]}`,
+			wantErr: true,
+		},
+		{
+			name: "widen int to long",
+			prev: widgetV1,
+			next: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"},
+				{"name":"count","type":"long"}
+			]}`,
+		},
+		{
+			name: "narrow long to int",
+			prev: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"},
+				{"name":"count","type":"long"}
+			]}`,
+			next:    widgetV1,
+			wantErr: true,
+		},
+		{
+			// BACKWARD-only: next just has to keep reading prev-written
+			// data, and a record decode ignores fields the reader schema
+			// doesn't declare, so dropping a field (with or without a
+			// default) is compatible regardless.
+			name: "remove field without default",
+			prev: widgetV1,
+			next: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"}
+			]}`,
+		},
+		{
+			name: "remove field with default",
+			prev: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"},
+				{"name":"count","type":"int","default":0}
+			]}`,
+			next: `{"type":"record","name":"Widget","namespace":"test","fields":[
+				{"name":"id","type":"string"}
+			]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := mustParseSchema(t, tt.prev)
+			next := mustParseSchema(t, tt.next)
+
+			err := CheckCompatibility(prev, next)
+			if tt.wantErr && err == nil {
+				t.Fatalf("CheckCompatibility() = nil, want error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("CheckCompatibility() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestFingerprintSchema(t *testing.T) {
+	a := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[{"name":"id","type":"string"}]}`)
+	b := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[{"name":"id","type":"string"}]}`)
+	c := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[{"name":"id","type":"long"}]}`)
+
+	fpA, err := fingerprintSchema(a)
+	if err != nil {
+		t.Fatalf("fingerprintSchema(a): %v", err)
+	}
+	fpB, err := fingerprintSchema(b)
+	if err != nil {
+		t.Fatalf("fingerprintSchema(b): %v", err)
+	}
+	fpC, err := fingerprintSchema(c)
+	if err != nil {
+		t.Fatalf("fingerprintSchema(c): %v", err)
+	}
+
+	if fpA != fpB {
+		t.Errorf("identical schemas got different fingerprints: %s vs %s", fpA, fpB)
+	}
+	if fpA == fpC {
+		t.Errorf("different schemas got the same fingerprint: %s", fpA)
+	}
+}
+
+func TestResolveAndUnmarshal(t *testing.T) {
+	type widget struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+		Note  string `json:"note"`
+	}
+
+	writerSchema := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"},
+		{"name":"count","type":"int"}
+	]}`)
+	readerSchema := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"},
+		{"name":"count","type":"int"},
+		{"name":"note","type":"string","default":"n/a"}
+	]}`)
+
+	var out widget
+	data := []byte(`{"id":"abc","count":3}`)
+	if err := resolveAndUnmarshal(writerSchema, readerSchema, data, &out); err != nil {
+		t.Fatalf("resolveAndUnmarshal: %v", err)
+	}
+
+	want := widget{ID: "abc", Count: 3, Note: "n/a"}
+	if out != want {
+		t.Fatalf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestResolveAndUnmarshalSameSchemaSkipsResolution(t *testing.T) {
+	type widget struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+	}
+
+	schema := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"},
+		{"name":"count","type":"int"}
+	]}`)
+
+	var out widget
+	data := []byte(`{"id":"abc","count":3}`)
+	if err := resolveAndUnmarshal(schema, schema, data, &out); err != nil {
+		t.Fatalf("resolveAndUnmarshal: %v", err)
+	}
+
+	want := widget{ID: "abc", Count: 3}
+	if out != want {
+		t.Fatalf("got %+v, want %+v", out, want)
+	}
+}
+
+func TestResolveAndUnmarshalMissingFieldWithoutDefaultErrors(t *testing.T) {
+	type widget struct {
+		ID   string `json:"id"`
+		Note string `json:"note"`
+	}
+
+	writerSchema := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"}
+	]}`)
+	readerSchema := mustParseSchema(t, `{"type":"record","name":"Widget","namespace":"test","fields":[
+		{"name":"id","type":"string"},
+		{"name":"note","type":"string"}
+	]}`)
+
+	var out widget
+	data := []byte(`{"id":"abc"}`)
+	if err := resolveAndUnmarshal(writerSchema, readerSchema, data, &out); err == nil {
+		t.Fatalf("resolveAndUnmarshal() = nil, want error")
+	}
+}
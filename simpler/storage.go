@@ -18,37 +18,66 @@ const (
 
 type StorageClient interface {
 	// UploadFile uploads a file to the specified bucket.
-	UploadFile(ctx context.Context, bucket string, name string, content io.Reader) error
+	UploadFile(ctx context.Context, bucket string, name string, content io.Reader, opts ...UploadOption) error
 
 	// UploadJSONSchematized uploads a JSON-serializable object to the specified bucket.
 	// It validates the object against its Avro schema before upload and stores the schema
 	// reference in the object's metadata for later validation during download.
-	UploadJSONSchematized(ctx context.Context, bucket string, name string, object SchemaProvider) error
+	UploadJSONSchematized(ctx context.Context, bucket string, name string, object SchemaProvider, opts ...UploadOption) error
 
 	// DownloadFile downloads a file from the specified bucket and returns its contents as bytes.
-	DownloadFile(ctx context.Context, bucket, name string) ([]byte, error)
+	DownloadFile(ctx context.Context, bucket, name string, opts ...DownloadOption) ([]byte, error)
 
 	// DownloadJSONSchematized downloads a JSON-serializable object from the specified bucket.
 	// It validates that the stored schema reference matches the expected schema and
 	// validates the downloaded object against the schema after unmarshaling.
-	DownloadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider) error
+	DownloadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider, opts ...DownloadOption) error
+
+	// UploadStream copies r to the specified bucket/name in chunks, without
+	// buffering the whole object in memory. It returns the resulting object's
+	// attributes, which callers can pass to VerifyChecksum. Use WithOffset to
+	// resume a previous partial upload when r can be re-read starting at that
+	// offset.
+	UploadStream(ctx context.Context, bucket, name string, r io.Reader, opts ...UploadOption) (*storage.ObjectAttrs, error)
+
+	// DownloadStream opens a streaming reader for the specified object along
+	// with its attributes. The caller must close the returned reader.
+	DownloadStream(ctx context.Context, bucket, name string) (io.ReadCloser, *storage.ObjectAttrs, error)
+
+	// DownloadRange opens a streaming reader over [offset, offset+length) of
+	// the specified object. A negative length reads through the end of the
+	// object. The caller must close the returned reader.
+	DownloadRange(ctx context.Context, bucket, name string, offset, length int64) (io.ReadCloser, error)
+
+	// List returns an iterator over the objects in bucket matching query.
+	List(ctx context.Context, bucket string, query ListQuery) ObjectIterator
 }
 
 type storageClient struct {
-	client *storage.Client
+	client         *storage.Client
+	retryPolicy    RetryPolicy
+	schemaRegistry SchemaRegistry
 }
 
 var _ StorageClient = (*storageClient)(nil)
 
-// NewStorageClient creates a new StorageGateway instance.
-func NewStorageClient(ctx context.Context) (StorageClient, error) {
-	client, err := storage.NewClient(ctx)
+// NewStorageClient creates a new StorageGateway instance. By default it
+// authenticates using application default credentials; pass Options to
+// customize the transport or point it at an emulator. If STORAGE_EMULATOR_HOST
+// is set, it is honored automatically unless overridden by an explicit
+// WithEndpoint option.
+func NewStorageClient(ctx context.Context, opts ...Option) (StorageClient, error) {
+	cfg := newClientConfig(storageEmulatorOptions(), opts)
+
+	client, err := storage.NewClient(ctx, cfg.clientOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("creating storage client: %w", err)
 	}
 
 	return &storageClient{
-		client: client,
+		client:         client,
+		retryPolicy:    cfg.retryPolicy,
+		schemaRegistry: cfg.schemaRegistry,
 	}, nil
 }
 
@@ -56,37 +85,147 @@ type SchemaProvider interface {
 	Schema() avro.Schema
 }
 
+// uploadConfig holds accumulated settings for a single upload call.
+type uploadConfig struct {
+	retryPolicy  RetryPolicy
+	doesNotExist bool
+	alwaysRetry  bool
+	chunkSize    int
+	offset       int64
+}
+
+// UploadOption configures a single UploadFile/UploadJSONSchematized call.
+type UploadOption func(*uploadConfig)
+
+// WithUploadRetry overrides the client's default retry policy for this call.
+func WithUploadRetry(policy RetryPolicy) UploadOption {
+	return func(c *uploadConfig) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithIfDoesNotExist fails the upload instead of overwriting an existing
+// object, via storage.Conditions{DoesNotExist: true}.
+func WithIfDoesNotExist() UploadOption {
+	return func(c *uploadConfig) {
+		c.doesNotExist = true
+	}
+}
+
+// WithRetryAlways opts a non-idempotent upload (e.g. one without
+// WithIfDoesNotExist) into retrying even when the client can't tell whether
+// the previous attempt's write already landed, via storage.RetryAlways.
+func WithRetryAlways() UploadOption {
+	return func(c *uploadConfig) {
+		c.alwaysRetry = true
+	}
+}
+
+// WithChunkSize sets the chunk size used by UploadStream. Larger chunks use
+// more memory per upload but mean fewer round trips; the default is 16MiB.
+func WithChunkSize(size int) UploadOption {
+	return func(c *uploadConfig) {
+		c.chunkSize = size
+	}
+}
+
+// WithOffset skips the first offset bytes of r before streaming the rest to
+// GCS, so a caller that tracked how much of a prior UploadStream attempt
+// landed can resume from where it left off instead of restarting.
+func WithOffset(offset int64) UploadOption {
+	return func(c *uploadConfig) {
+		c.offset = offset
+	}
+}
+
+// downloadConfig holds accumulated settings for a single download call.
+type downloadConfig struct {
+	retryPolicy RetryPolicy
+}
+
+// DownloadOption configures a single DownloadFile/DownloadJSONSchematized call.
+type DownloadOption func(*downloadConfig)
+
+// WithDownloadRetry overrides the client's default retry policy for this call.
+func WithDownloadRetry(policy RetryPolicy) DownloadOption {
+	return func(c *downloadConfig) {
+		c.retryPolicy = policy
+	}
+}
+
 // upload writes the provided content to the specified GCS object in bucket/name.
 // If contentType is non-empty, it is set on the object. If metadata is non-nil,
 // its key-value pairs are attached as user-defined object metadata.
 // The function copies all bytes from content and ensures the writer is closed,
-// returning any encountered write/close errors wrapped with context.
-func (c *storageClient) upload(ctx context.Context, bucket, name string, content io.Reader, contentType string, metadata map[string]string) error {
+// retrying transient failures per cfg.retryPolicy, and returns any encountered
+// write/close errors wrapped with context.
+func (c *storageClient) upload(ctx context.Context, bucket, name string, content io.Reader, contentType string, metadata map[string]string, cfg uploadConfig) error {
 	obj := c.client.Bucket(bucket).Object(name)
-	wc := obj.NewWriter(ctx)
-
-	if contentType != "" {
-		wc.ContentType = contentType
+	if cfg.doesNotExist {
+		obj = obj.If(storage.Conditions{DoesNotExist: true})
 	}
-	if metadata != nil {
-		wc.Metadata = metadata
+	if cfg.alwaysRetry {
+		obj = obj.Retryer(storage.WithPolicy(storage.RetryAlways))
 	}
 
-	if _, err := io.Copy(wc, content); err != nil {
-		return fmt.Errorf("copying file to artifacts bucket: %w", err)
+	// Buffer content so a retried attempt can replay it from the start;
+	// the reader given to us may not be seekable.
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("buffering content for upload: %w", err)
 	}
 
-	if err := wc.Close(); err != nil {
-		return fmt.Errorf("closing bucket writer: %w", err)
+	err = withRetry(ctx, cfg.retryPolicy, func() error {
+		wc := obj.NewWriter(ctx)
+		if contentType != "" {
+			wc.ContentType = contentType
+		}
+		if metadata != nil {
+			wc.Metadata = metadata
+		}
+
+		if _, err := io.Copy(wc, bytes.NewReader(data)); err != nil {
+			_ = wc.Close()
+			return fmt.Errorf("copying file to artifacts bucket: %w", err)
+		}
+
+		if err := wc.Close(); err != nil {
+			return fmt.Errorf("closing bucket writer: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	return nil
 }
 
+func mergeUploadConfig(defaultPolicy RetryPolicy, opts []UploadOption) uploadConfig {
+	cfg := uploadConfig{retryPolicy: defaultPolicy}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+func mergeDownloadConfig(defaultPolicy RetryPolicy, opts []DownloadOption) downloadConfig {
+	cfg := downloadConfig{retryPolicy: defaultPolicy}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
 // UploadJSONSchematized uploads a JSON-serializable object to the specified bucket.
-// It also validates the object against the provided Avro schema.
-// And add in bucket meta schema name
-func (c *storageClient) UploadJSONSchematized(ctx context.Context, bucket string, name string, object SchemaProvider) error {
+// It also validates the object against the provided Avro schema, and records
+// the schema name in the object's metadata. If the client was constructed
+// with WithSchemaRegistry, it additionally registers the schema there and
+// records its fingerprint (and, where the registry assigns one, its ID), so
+// DownloadJSONSchematized can resolve the object against the writer's exact
+// schema rather than only checking the schema name.
+func (c *storageClient) UploadJSONSchematized(ctx context.Context, bucket string, name string, object SchemaProvider, opts ...UploadOption) error {
 	// validate
 	err := avro.NewEncoderForSchema(object.Schema(), io.Discard).Encode(object)
 	if err != nil {
@@ -99,61 +238,106 @@ func (c *storageClient) UploadJSONSchematized(ctx context.Context, bucket string
 		return fmt.Errorf("marshaling object: %w", err)
 	}
 
-	return c.upload(ctx, bucket, name, bytes.NewReader(data), "application/json", map[string]string{
+	metadata := map[string]string{
 		schemaRefKey: object.(avro.NamedSchema).Name(),
-	})
+	}
+
+	if c.schemaRegistry != nil {
+		reg, err := c.schemaRegistry.Register(ctx, object.Schema())
+		if err != nil {
+			return fmt.Errorf("registering schema: %w", err)
+		}
+		metadata[schemaFPKey] = reg.Fingerprint
+		if reg.ID != "" {
+			metadata[schemaIDKey] = reg.ID
+		}
+	}
+
+	cfg := mergeUploadConfig(c.retryPolicy, opts)
+	return c.upload(ctx, bucket, name, bytes.NewReader(data), "application/json", metadata, cfg)
 }
 
 // UploadFile uploads a file to the specified bucket.
-func (c *storageClient) UploadFile(ctx context.Context, bucket string, name string, content io.Reader) error {
-	return c.upload(ctx, bucket, name, content, "", nil)
+func (c *storageClient) UploadFile(ctx context.Context, bucket string, name string, content io.Reader, opts ...UploadOption) error {
+	cfg := mergeUploadConfig(c.retryPolicy, opts)
+	return c.upload(ctx, bucket, name, content, "", nil, cfg)
 }
 
 // readObject reads the full contents of the given GCS object and returns the bytes.
-// It centralizes opening/closing the reader and wraps read errors with context.
-// Callers should prefer this helper from Download* methods.
-func (c *storageClient) readObject(ctx context.Context, obj *storage.ObjectHandle) ([]byte, error) {
-	r, err := obj.NewReader(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("creating reader: %w", err)
-	}
-	defer r.Close()
+// It centralizes opening/closing the reader, retries transient failures per
+// policy, and wraps read errors with context. Callers should prefer this
+// helper from Download* methods.
+func (c *storageClient) readObject(ctx context.Context, obj *storage.ObjectHandle, policy RetryPolicy) ([]byte, error) {
+	var data []byte
+	err := withRetry(ctx, policy, func() error {
+		r, err := obj.NewReader(ctx)
+		if err != nil {
+			return fmt.Errorf("creating reader: %w", err)
+		}
+		defer r.Close()
 
-	data, err := io.ReadAll(r)
+		data, err = io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("reading file: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("reading file: %w", err)
+		return nil, err
 	}
 	return data, nil
 }
 
 // DownloadFile downloads a file from the specified bucket and returns its contents as bytes.
-func (c *storageClient) DownloadFile(ctx context.Context, bucket, name string) ([]byte, error) {
+func (c *storageClient) DownloadFile(ctx context.Context, bucket, name string, opts ...DownloadOption) ([]byte, error) {
+	cfg := mergeDownloadConfig(c.retryPolicy, opts)
 	obj := c.client.Bucket(bucket).Object(name)
-	return c.readObject(ctx, obj)
+	return c.readObject(ctx, obj, cfg.retryPolicy)
 }
 
 // DownloadJSONSchematized downloads a JSON-serializable object from the specified bucket.
 // Also validate the object against the provided Avro schema.
-func (c *storageClient) DownloadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider) error {
+//
+// If the client was constructed with WithSchemaRegistry and the object
+// carries a schema_fp, the writer's schema is looked up by fingerprint and
+// the download is resolved against it via resolveAndUnmarshal, so a writer
+// schema that evolved compatibly from (or to) object's schema can still be
+// read. Otherwise it falls back to checking schema_ref against the schema
+// name, as before.
+func (c *storageClient) DownloadJSONSchematized(ctx context.Context, bucket, name string, object SchemaProvider, opts ...DownloadOption) error {
+	cfg := mergeDownloadConfig(c.retryPolicy, opts)
 	obj := c.client.Bucket(bucket).Object(name)
 	attrs, err := obj.Attrs(ctx)
 	if err != nil {
 		return fmt.Errorf("getting attrs: %w", err)
 	}
 
-	schemaRef := attrs.Metadata[schemaRefKey]
 	ns, ok := object.Schema().(avro.NamedSchema)
-	if !ok || schemaRef != ns.Name() {
-		return fmt.Errorf("schema mismatch or missing schema_ref: have=%q want=%q", schemaRef, ns.Name())
+	if !ok {
+		return fmt.Errorf("schema for %T is not a named schema", object)
 	}
 
-	data, err := c.readObject(ctx, obj)
+	data, err := c.readObject(ctx, obj, cfg.retryPolicy)
 	if err != nil {
 		return err
 	}
 
-	if err := json.Unmarshal(data, object); err != nil {
-		return fmt.Errorf("json: %w", err)
+	if fp := attrs.Metadata[schemaFPKey]; c.schemaRegistry != nil && fp != "" {
+		writerSchema, err := c.schemaRegistry.Get(ctx, fp)
+		if err != nil {
+			return fmt.Errorf("looking up writer schema %s: %w", fp, err)
+		}
+		if err := resolveAndUnmarshal(writerSchema, object.Schema(), data, object); err != nil {
+			return fmt.Errorf("resolving schema evolution: %w", err)
+		}
+	} else {
+		schemaRef := attrs.Metadata[schemaRefKey]
+		if schemaRef != ns.Name() {
+			return fmt.Errorf("schema mismatch or missing schema_ref: have=%q want=%q", schemaRef, ns.Name())
+		}
+		if err := json.Unmarshal(data, object); err != nil {
+			return fmt.Errorf("json: %w", err)
+		}
 	}
 
 	// validate schema
@@ -0,0 +1,109 @@
+package simpler
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"cloud.google.com/go/storage"
+)
+
+// defaultChunkSize is the chunk size UploadStream uses when the caller
+// doesn't set WithChunkSize.
+const defaultChunkSize = 16 * 1024 * 1024 // 16MiB
+
+// UploadStream copies r to the specified bucket/name in chunks, without
+// buffering the whole object in memory, making it suitable for multi-GB
+// artifacts. Unlike UploadFile, a failed attempt is not retried internally
+// because r may not be re-readable from the start; callers can instead track
+// how many bytes they've already written and resume with WithOffset.
+func (c *storageClient) UploadStream(ctx context.Context, bucket, name string, r io.Reader, opts ...UploadOption) (*storage.ObjectAttrs, error) {
+	cfg := mergeUploadConfig(c.retryPolicy, opts)
+	if cfg.chunkSize == 0 {
+		cfg.chunkSize = defaultChunkSize
+	}
+
+	if cfg.offset > 0 {
+		if _, err := io.CopyN(io.Discard, r, cfg.offset); err != nil {
+			return nil, fmt.Errorf("skipping already-uploaded offset %d: %w", cfg.offset, err)
+		}
+	}
+
+	obj := c.client.Bucket(bucket).Object(name)
+	if cfg.doesNotExist {
+		obj = obj.If(storage.Conditions{DoesNotExist: true})
+	}
+	if cfg.alwaysRetry {
+		obj = obj.Retryer(storage.WithPolicy(storage.RetryAlways))
+	}
+
+	wc := obj.NewWriter(ctx)
+	wc.ChunkSize = cfg.chunkSize
+
+	if _, err := io.Copy(wc, r); err != nil {
+		_ = wc.Close()
+		return nil, fmt.Errorf("streaming upload to %s/%s: %w", bucket, name, err)
+	}
+
+	if err := wc.Close(); err != nil {
+		return nil, fmt.Errorf("closing bucket writer: %w", err)
+	}
+
+	return wc.Attrs(), nil
+}
+
+// DownloadStream opens a streaming reader for the specified object along
+// with its attributes. The caller must close the returned reader.
+func (c *storageClient) DownloadStream(ctx context.Context, bucket, name string) (io.ReadCloser, *storage.ObjectAttrs, error) {
+	obj := c.client.Bucket(bucket).Object(name)
+
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating reader: %w", err)
+	}
+
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		r.Close()
+		return nil, nil, fmt.Errorf("getting attrs: %w", err)
+	}
+
+	return r, attrs, nil
+}
+
+// DownloadRange opens a streaming reader over [offset, offset+length) of the
+// specified object. A negative length reads through the end of the object.
+// The caller must close the returned reader.
+func (c *storageClient) DownloadRange(ctx context.Context, bucket, name string, offset, length int64) (io.ReadCloser, error) {
+	obj := c.client.Bucket(bucket).Object(name)
+
+	r, err := obj.NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("creating range reader: %w", err)
+	}
+
+	return r, nil
+}
+
+// VerifyChecksum checks data against the CRC32C and/or MD5 digests recorded
+// in attrs (as returned by UploadStream or obj.Attrs), returning an error
+// describing any mismatch. Digests attrs doesn't have set are skipped.
+func VerifyChecksum(attrs *storage.ObjectAttrs, data []byte) error {
+	if attrs.CRC32C != 0 {
+		if got := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)); got != attrs.CRC32C {
+			return fmt.Errorf("crc32c mismatch: have=%d want=%d", got, attrs.CRC32C)
+		}
+	}
+
+	if len(attrs.MD5) > 0 {
+		sum := md5.Sum(data)
+		if !bytes.Equal(sum[:], attrs.MD5) {
+			return fmt.Errorf("md5 mismatch: have=%x want=%x", sum, attrs.MD5)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package simpler
+
+import (
+	"crypto/md5"
+	"hash/crc32"
+	"testing"
+
+	"cloud.google.com/go/storage"
+)
+
+func TestVerifyChecksumMatches(t *testing.T) {
+	data := []byte("stream me")
+	md5sum := md5.Sum(data)
+	crc := crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+
+	attrs := &storage.ObjectAttrs{CRC32C: crc, MD5: md5sum[:]}
+	if err := VerifyChecksum(attrs, data); err != nil {
+		t.Fatalf("VerifyChecksum() = %v, want nil", err)
+	}
+}
+
+func TestVerifyChecksumCRC32CMismatch(t *testing.T) {
+	attrs := &storage.ObjectAttrs{CRC32C: 12345}
+	if err := VerifyChecksum(attrs, []byte("data")); err == nil {
+		t.Fatalf("VerifyChecksum() = nil, want crc32c mismatch error")
+	}
+}
+
+func TestVerifyChecksumMD5Mismatch(t *testing.T) {
+	attrs := &storage.ObjectAttrs{MD5: []byte("not-a-real-digest")}
+	if err := VerifyChecksum(attrs, []byte("data")); err == nil {
+		t.Fatalf("VerifyChecksum() = nil, want md5 mismatch error")
+	}
+}
+
+func TestVerifyChecksumSkipsUnsetDigests(t *testing.T) {
+	if err := VerifyChecksum(&storage.ObjectAttrs{}, []byte("anything")); err != nil {
+		t.Fatalf("VerifyChecksum() = %v, want nil when no digests are set", err)
+	}
+}